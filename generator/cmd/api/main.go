@@ -1,33 +1,68 @@
 package main
 
 import (
-	"github.com/ezegrosfeld/wallet/generator/cmd/routes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
 
-	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
+	"github.com/ezegrosfeld/wallet/generator/cmd/routes"
+	"github.com/ezegrosfeld/wallet/generator/pkg/config"
+	"github.com/ezegrosfeld/wallet/generator/pkg/flags"
+	"github.com/ezegrosfeld/wallet/generator/pkg/jwe"
+	"github.com/ezegrosfeld/wallet/generator/pkg/service"
 )
 
+type appConfig struct {
+	Addr            string        `yaml:"addr" env:"ADDR" flag:"addr" default:":8080" usage:"address to listen on"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"10s" usage:"time given to drain requests on shutdown"`
+	DrainDelay      time.Duration `yaml:"drain_delay" env:"DRAIN_DELAY" flag:"drain-delay" default:"5s" usage:"time requests keep being served with readiness failed on shutdown"`
+	AdminToken      string        `yaml:"admin_token" env:"ADMIN_TOKEN"`
+	JWEKeys         string        `yaml:"jwe_keys" env:"JWE_KEYS" flag:"jwe-keys" usage:"directory of the <kid>.pem keys decrypting seeds sent in a JWE envelope"`
+}
+
+func (c *appConfig) Validate() error {
+	if c.DrainDelay >= c.ShutdownTimeout {
+		return fmt.Errorf("drain delay (%s) must be shorter than the shutdown timeout (%s)", c.DrainDelay, c.ShutdownTimeout)
+	}
+	return nil
+}
+
 func main() {
-	// Create a new gin router
-	router := gin.Default()
+	cfg := appConfig{}
+	if err := config.Load(&cfg, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	// Create a new logger
-	l, err := zap.NewProduction()
+	s, err := service.New("generator", cfg.Addr)
 	if err != nil {
 		panic(err)
 	}
 
-	sl := l.Sugar()
+	s.ShutdownTimeout = cfg.ShutdownTimeout
+	s.DrainDelay = cfg.DrainDelay
 
-	routes.MapRoutes(sl, router)
+	// Seeds are only accepted in a JWE envelope when keys are configured
+	var keys *jwe.KeySet
+	if cfg.JWEKeys != "" {
+		if keys, err = jwe.LoadKeySet(cfg.JWEKeys); err != nil {
+			s.Log.Fatal(err)
+		}
+	}
 
-	healthCheck(router)
+	routes.MapRoutes(s.Log, s.Router, keys)
 
-	router.Run(":8080")
-}
+	// The admin API is only served when a token is configured
+	if cfg.AdminToken != "" {
+		flags.RegisterAdmin(s.Admin(cfg.AdminToken), flags.Default)
+	}
 
-func healthCheck(r *gin.Engine) {
-	r.GET("/health", func(c *gin.Context) {
-		c.String(200, "OK")
-	})
+	if err := s.Run(); err != nil {
+		s.Log.Fatal(err)
+	}
 }
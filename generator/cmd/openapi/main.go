@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/ezegrosfeld/wallet/generator/cmd/routes"
+)
+
+// Writes the OpenAPI document of the generator, CI checks the committed
+// file is up to date
+func main() {
+	out := flag.String("o", "", "file to write the document to, stdout if empty")
+	flag.Parse()
+
+	b, err := json.MarshalIndent(routes.Spec(), "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	b = append(b, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+
+	if err := ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
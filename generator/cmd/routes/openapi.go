@@ -0,0 +1,146 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/ezegrosfeld/wallet/generator/pkg/openapi"
+)
+
+// Spec describes the routes mapped by MapRoutes, keep it in sync when
+// adding or changing a route
+func Spec() openapi.Document {
+	errorResponse := func(description string) openapi.Response {
+		return openapi.JSON(description, openapi.Ref("Error"))
+	}
+
+	create := openapi.Operation{
+		Summary:     "Generate a new seed and its first address",
+		OperationID: "createWallet",
+		Responses: map[string]openapi.Response{
+			"201": openapi.JSON("Wallet created", openapi.Ref("Wallet")),
+			"500": errorResponse("The wallet could not be generated"),
+		},
+	}
+
+	get := openapi.Operation{
+		Summary:     "Derive the address of a seed at an index",
+		OperationID: "getWallet",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        "seed",
+				In:          "query",
+				Description: "Mnemonic of the wallet",
+				Required:    true,
+				Schema:      openapi.Schema{Type: "string"},
+			},
+			{
+				Name:        "index",
+				In:          "query",
+				Description: "Index of the address in the wallet, hardened indexes are not accepted",
+				Required:    true,
+				Schema:      openapi.Schema{Type: "integer", Format: "int64", Minimum: openapi.Int(0), Maximum: openapi.Int(2147483647)},
+			},
+		},
+		Responses: map[string]openapi.Response{
+			"200": openapi.JSON("Address of the wallet", openapi.Ref("Wallet")),
+			"400": errorResponse("Missing seed or invalid index"),
+			"500": errorResponse("The address could not be derived"),
+		},
+	}
+
+	derive := openapi.Operation{
+		Summary:     "Derive the address of a seed at an index",
+		OperationID: "deriveWallet",
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {Schema: openapi.Ref("DeriveRequest")},
+			},
+		},
+		Responses: map[string]openapi.Response{
+			"200": openapi.JSON("Address of the wallet", openapi.Ref("Wallet")),
+			"400": errorResponse("Missing seed or invalid index"),
+			"500": errorResponse("The address could not be derived"),
+		},
+	}
+
+	return openapi.Document{
+		OpenAPI: "3.0.3",
+		Info: openapi.Info{
+			Title:   "Generator",
+			Version: "2.0.0",
+		},
+		Paths: map[string]openapi.PathItem{
+			"/wallet/": {
+				"post": deprecated(create),
+				"get":  deprecated(get),
+			},
+			"/v1/wallet/": {
+				"post": versioned("v1", create),
+				"get":  versioned("v1", get),
+			},
+			"/v2/wallet/": {
+				"post": versioned("v2", create),
+			},
+			"/v2/wallet/derive": {
+				"post": versioned("v2", derive),
+			},
+		},
+		Components: openapi.Components{
+			Schemas: map[string]openapi.Schema{
+				"Wallet": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"seed":    {Type: "string", Description: "Mnemonic of the wallet, only returned on creation"},
+						"address": {Type: "string"},
+						"index":   {Type: "integer", Description: "Index of the address in the wallet"},
+					},
+					Required: []string{"address"},
+				},
+				"DeriveRequest": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"seed":     {Type: "string", Description: "Mnemonic of the wallet, required unless seed_jwe is sent"},
+						"seed_jwe": {Type: "string", Description: "Mnemonic of the wallet sealed in a JWE compact token (RSA-OAEP-256, A256GCM), instead of seed"},
+						"index": {
+							Type:        "integer",
+							Description: "Index of the address in the wallet, hardened indexes are not accepted",
+							Minimum:     openapi.Int(0),
+							Maximum:     openapi.Int(2147483647),
+						},
+					},
+				},
+				"Error": {
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"error": {Type: "string"},
+					},
+					Required: []string{"error"},
+				},
+			},
+		},
+	}
+}
+
+// versioned prefixes the operation ID with the API version, as IDs must be
+// unique across the document
+func versioned(version string, op openapi.Operation) openapi.Operation {
+	op.OperationID = version + strings.Title(op.OperationID)
+	return op
+}
+
+// deprecated marks an unversioned operation kept as an alias of v1
+func deprecated(op openapi.Operation) openapi.Operation {
+	op.OperationID = "legacy" + strings.Title(op.OperationID)
+	op.Deprecated = true
+
+	responses := map[string]openapi.Response{
+		"410": openapi.JSON("Legacy routes are turned off ahead of their sunset", openapi.Ref("Error")),
+	}
+	for code, r := range op.Responses {
+		responses[code] = r
+	}
+	op.Responses = responses
+
+	return op
+}
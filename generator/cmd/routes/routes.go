@@ -1,18 +1,57 @@
 package routes
 
 import (
+	"net/http"
+	"time"
+
 	address "github.com/ezegrosfeld/wallet/generator/internal/wallet"
+	"github.com/ezegrosfeld/wallet/generator/pkg/apiversion"
+	"github.com/ezegrosfeld/wallet/generator/pkg/flags"
+	"github.com/ezegrosfeld/wallet/generator/pkg/jwe"
+	"github.com/ezegrosfeld/wallet/generator/pkg/openapi"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func MapRoutes(log *zap.SugaredLogger, router *gin.Engine) {
+var (
+	v1 = apiversion.Version{Name: "v1"}
+	v2 = apiversion.Version{Name: "v2"}
+
+	// Routes served before versioning, kept as an alias of v1 until sunset
+	legacy = apiversion.Version{
+		Name:       "v1",
+		Deprecated: true,
+		Sunset:     time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+		Successor:  "/v1",
+	}
+)
+
+// Flag serving the legacy routes, turned off for brownouts before sunset
+const LegacyRoutesFlag = "legacy_wallet_routes"
+
+// MapRoutes maps the wallet routes, keys decrypting seeds sent in a JWE
+// envelope if not nil
+func MapRoutes(log *zap.SugaredLogger, router *gin.Engine, keys *jwe.KeySet) {
 	// Create address handler
 	service := address.NewService(log)
-	handler := address.NewHandler(service, log)
+	handler := address.NewHandler(service, log, keys)
+
+	flags.Define(flags.Flag{Name: LegacyRoutesFlag, Enabled: true})
 
 	// Map routes
-	wallet := router.Group("/wallet")
+	for _, g := range []*gin.RouterGroup{
+		legacy.Group(router, "/", flags.Require(flags.Default, LegacyRoutesFlag, http.StatusGone)),
+		v1.Group(router, "/v1"),
+	} {
+		wallet := g.Group("/wallet")
+		wallet.POST("/", handler.Create())
+		wallet.GET("/", handler.Get())
+	}
+
+	wallet := v2.Group(router, "/v2").Group("/wallet")
 	wallet.POST("/", handler.Create())
-	wallet.GET("/", handler.Get())
+	wallet.POST("/derive", handler.Derive())
+
+	// Serve the API documentation
+	openapi.Register(router, Spec())
 }
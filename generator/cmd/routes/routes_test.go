@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ezegrosfeld/wallet/generator/pkg/flags"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -12,8 +16,83 @@ import (
 func TestRouteMapping(t *testing.T) {
 	router := gin.Default()
 
-	MapRoutes(&zap.SugaredLogger{}, router)
+	MapRoutes(&zap.SugaredLogger{}, router, nil)
 
 	i := router.Routes()
-	assert.Equal(t, len(i), 2)
+	assert.Equal(t, len(i), 10)
+}
+
+// Test that every mapped route is described by the spec
+func TestSpecCoversRoutes(t *testing.T) {
+	router := gin.Default()
+
+	MapRoutes(&zap.SugaredLogger{}, router, nil)
+
+	spec := Spec()
+	for _, r := range router.Routes() {
+		if strings.HasPrefix(r.Path, "/docs") || r.Path == "/openapi.json" {
+			continue
+		}
+
+		_, ok := spec.Paths[r.Path][strings.ToLower(r.Method)]
+		assert.True(t, ok, "%s %s is not described by the spec", r.Method, r.Path)
+	}
+}
+
+// Test the version headers of versioned and legacy routes
+func TestRouteVersions(t *testing.T) {
+	router := gin.Default()
+
+	MapRoutes(&zap.SugaredLogger{}, router, nil)
+
+	for path, version := range map[string]string{"/wallet/": "v1", "/v1/wallet/": "v1"} {
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+
+		assert.Equal(t, version, rw.Header().Get("API-Version"))
+		assert.Equal(t, path == "/wallet/", rw.Header().Get("Deprecation") == "true")
+	}
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("POST", "/v2/wallet/derive", nil))
+
+	assert.Equal(t, "v2", rw.Header().Get("API-Version"))
+}
+
+// Test that v1 and legacy routes reject indexes out of range
+func TestRouteInvalidIndex(t *testing.T) {
+	router := gin.Default()
+
+	MapRoutes(zap.NewNop().Sugar(), router, nil)
+
+	for _, path := range []string{"/v1/wallet/", "/wallet/"} {
+		for _, index := range []string{"-1", "2147483648"} {
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, httptest.NewRequest("GET", path+"?seed=seed&index="+index, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rw.Code, path+index)
+			assert.Contains(t, rw.Body.String(), `"error"`, path+index)
+		}
+	}
+}
+
+// Test that legacy routes can be turned off
+func TestLegacyRoutesFlag(t *testing.T) {
+	router := gin.Default()
+
+	MapRoutes(&zap.SugaredLogger{}, router, nil)
+
+	assert.NoError(t, flags.Default.Set(flags.Flag{Name: LegacyRoutesFlag}))
+	defer flags.Default.Set(flags.Flag{Name: LegacyRoutesFlag, Enabled: true})
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("GET", "/wallet/", nil))
+
+	assert.Equal(t, http.StatusGone, rw.Code)
+	assert.Equal(t, "true", rw.Header().Get("Deprecation"))
+
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("GET", "/v1/wallet/", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
 }
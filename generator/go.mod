@@ -6,7 +6,13 @@ require (
 	github.com/ethereum/go-ethereum v1.10.8 // indirect
 	github.com/gin-gonic/gin v1.7.4
 	github.com/miguelmota/go-ethereum-hdwallet v0.1.1
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/prometheus/client_golang v1.11.0
+	github.com/stretchr/testify v1.7.0
 	github.com/tyler-smith/go-bip39 v1.1.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	go.uber.org/zap v1.19.0
+	gopkg.in/yaml.v2 v2.4.0
 )
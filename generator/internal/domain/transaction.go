@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ezegrosfeld/wallet/generator/internal/statemachine"
+)
+
+type TransactionState = statemachine.State
+
+const (
+	TransactionInitiated TransactionState = "initiated"
+	TransactionPending   TransactionState = "pending"
+	TransactionSettled   TransactionState = "settled"
+	TransactionFailed    TransactionState = "failed"
+	TransactionReversed  TransactionState = "reversed"
+)
+
+// Time a transaction may stay pending before it is considered failed
+const TransactionPendingTimeout = 24 * time.Hour
+
+// Lifecycle of a transaction, any state without transitions is final
+var transactionMachine = statemachine.New(TransactionInitiated).
+	Allow(TransactionInitiated, TransactionPending, TransactionFailed).
+	Allow(TransactionPending, TransactionSettled, TransactionFailed).
+	Allow(TransactionSettled, TransactionReversed).
+	Timeout(TransactionPending, TransactionPendingTimeout, TransactionFailed)
+
+// TransactionLifecycleDOT exports the transaction lifecycle as a graphviz digraph
+func TransactionLifecycleDOT() string {
+	return transactionMachine.DOT("transaction")
+}
+
+// Clock used to timestamp transitions, replaced in tests
+var now = time.Now
+
+type Transition struct {
+	From TransactionState `json:"from,omitempty"`
+	To   TransactionState `json:"to"`
+	At   time.Time        `json:"at"`
+}
+
+type Transaction struct {
+	ID          string           `json:"id"`
+	From        string           `json:"from"`  // Address debited
+	To          string           `json:"to"`    // Address credited
+	Value       *big.Int         `json:"value"` // Value transferred in wei
+	State       TransactionState `json:"state"`
+	Transitions []Transition     `json:"transitions"`
+}
+
+// TransactionNotifier is called every time a transaction changes its state
+type TransactionNotifier interface {
+	Notify(t Transaction, tr Transition) error
+}
+
+// NewTransaction creates a transaction in the initiated state
+func NewTransaction(id, from, to string, value *big.Int) *Transaction {
+	return &Transaction{
+		ID:    id,
+		From:  from,
+		To:    to,
+		Value: value,
+		State: TransactionInitiated,
+		Transitions: []Transition{{
+			To: TransactionInitiated,
+			At: now(),
+		}},
+	}
+}
+
+// CanTransition reports whether the transaction may move to the given state
+func (t *Transaction) CanTransition(to TransactionState) bool {
+	return transactionMachine.Can(t.State, to)
+}
+
+// TransitionTo moves the transaction to the given state, recording the
+// transition and notifying n if it is not nil. Guards of the lifecycle are
+// given the transaction.
+func (t *Transaction) TransitionTo(to TransactionState, n TransactionNotifier) error {
+	if err := transactionMachine.Transition(t, t.State, to); err != nil {
+		return err
+	}
+
+	tr := Transition{
+		From: t.State,
+		To:   to,
+		At:   now(),
+	}
+
+	t.State = to
+	t.Transitions = append(t.Transitions, tr)
+
+	if n == nil {
+		return nil
+	}
+
+	return n.Notify(*t, tr)
+}
+
+// Expire fails the transaction if it has been held in its current state for
+// longer than the lifecycle allows, reporting whether it did. Transactions
+// without transitions cannot tell since when they are held and are rejected.
+func (t *Transaction) Expire(n TransactionNotifier) (bool, error) {
+	if len(t.Transitions) == 0 {
+		return false, fmt.Errorf("transaction %s has no recorded transitions", t.ID)
+	}
+
+	since := t.Transitions[len(t.Transitions)-1].At
+
+	to, ok := transactionMachine.Expired(t.State, since, now())
+	if !ok {
+		return false, nil
+	}
+
+	return true, t.TransitionTo(to, n)
+}
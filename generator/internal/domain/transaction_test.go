@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingNotifier struct {
+	transitions []Transition
+	err         error
+}
+
+func (r *recordingNotifier) Notify(t Transaction, tr Transition) error {
+	r.transitions = append(r.transitions, tr)
+	return r.err
+}
+
+// Test the full lifecycle of a transaction
+func TestTransactionLifecycle(t *testing.T) {
+	at := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	defer func() { now = time.Now }()
+
+	n := &recordingNotifier{}
+	tx := NewTransaction("id", "from", "to", big.NewInt(1))
+	assert.Equal(t, TransactionInitiated, tx.State)
+
+	assert.NoError(t, tx.TransitionTo(TransactionPending, n))
+	assert.NoError(t, tx.TransitionTo(TransactionSettled, n))
+	assert.NoError(t, tx.TransitionTo(TransactionReversed, n))
+
+	assert.Equal(t, TransactionReversed, tx.State)
+	assert.Len(t, tx.Transitions, 4)
+	assert.Len(t, n.transitions, 3)
+	assert.Equal(t, Transition{From: TransactionSettled, To: TransactionReversed, At: at}, n.transitions[2])
+}
+
+// Test that invalid transitions are rejected
+func TestTransactionInvalidTransition(t *testing.T) {
+	n := &recordingNotifier{}
+	tx := NewTransaction("id", "from", "to", big.NewInt(1))
+
+	assert.Error(t, tx.TransitionTo(TransactionSettled, n))
+	assert.NoError(t, tx.TransitionTo(TransactionFailed, n))
+	assert.Error(t, tx.TransitionTo(TransactionReversed, n))
+
+	assert.Equal(t, TransactionFailed, tx.State)
+	assert.Len(t, n.transitions, 1)
+}
+
+// Test that notifier errors are returned after the transition is recorded
+func TestTransactionNotifierError(t *testing.T) {
+	n := &recordingNotifier{err: fmt.Errorf("webhook unavailable")}
+	tx := NewTransaction("id", "from", "to", big.NewInt(1))
+
+	assert.Error(t, tx.TransitionTo(TransactionPending, n))
+	assert.Equal(t, TransactionPending, tx.State)
+}
+
+// Test that pending transactions fail after the timeout
+func TestTransactionExpire(t *testing.T) {
+	at := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	defer func() { now = time.Now }()
+
+	n := &recordingNotifier{}
+	tx := NewTransaction("id", "from", "to", big.NewInt(1))
+	assert.NoError(t, tx.TransitionTo(TransactionPending, n))
+
+	expired, err := tx.Expire(n)
+	assert.NoError(t, err)
+	assert.False(t, expired)
+
+	at = at.Add(TransactionPendingTimeout)
+
+	expired, err = tx.Expire(n)
+	assert.NoError(t, err)
+	assert.True(t, expired)
+	assert.Equal(t, TransactionFailed, tx.State)
+}
+
+// Test that transactions without transitions are not expired
+func TestTransactionExpireNoTransitions(t *testing.T) {
+	tx := &Transaction{ID: "id", State: TransactionPending}
+
+	expired, err := tx.Expire(nil)
+	assert.Error(t, err)
+	assert.False(t, expired)
+	assert.Equal(t, TransactionPending, tx.State)
+}
+
+// Test the graphviz export of the lifecycle
+func TestTransactionLifecycleDOT(t *testing.T) {
+	dot := TransactionLifecycleDOT()
+
+	assert.Contains(t, dot, `"initiated" [shape=doublecircle];`)
+	assert.Contains(t, dot, `"pending" -> "failed" [label="after 24h0m0s"];`)
+	assert.Contains(t, dot, `"settled" -> "reversed";`)
+}
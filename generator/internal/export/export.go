@@ -0,0 +1,82 @@
+package export
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+)
+
+// Exporter streams transactions of a single wallet address to a file format
+type Exporter interface {
+	// Write appends a transaction to the export, skipping it unless it has
+	// been settled. Reversed transactions are followed by an offsetting entry.
+	Write(tx domain.Transaction) error
+	// Close writes anything the format needs after the last transaction
+	Close() error
+}
+
+// Number of decimals of a value expressed in wei
+const decimals = 18
+
+// entry is a line of an export, booking a settled transaction or the
+// reversal offsetting it
+type entry struct {
+	id           string
+	at           time.Time
+	value        *big.Int // Value in wei as seen from the account, negative when debited
+	counterparty string
+}
+
+// entries returns the lines tx books on account: one once settled, and an
+// opposite one dated at the reversal if it was reversed
+func entries(tx domain.Transaction, account string) []entry {
+	if tx.State != domain.TransactionSettled && tx.State != domain.TransactionReversed {
+		return nil
+	}
+
+	v := new(big.Int)
+	if tx.Value != nil {
+		v.Set(tx.Value)
+	}
+
+	other := tx.From
+	if strings.EqualFold(tx.From, account) {
+		v.Neg(v)
+		other = tx.To
+	}
+
+	var l []entry
+	for _, tr := range tx.Transitions {
+		switch tr.To {
+		case domain.TransactionSettled:
+			l = append(l, entry{id: tx.ID, at: tr.At.UTC(), value: v, counterparty: other})
+		case domain.TransactionReversed:
+			l = append(l, entry{id: tx.ID + "-reversal", at: tr.At.UTC(), value: new(big.Int).Neg(v), counterparty: other})
+		}
+	}
+
+	return l
+}
+
+// amount formats a value in wei as ether
+func amount(v *big.Int) string {
+	s := v.String()
+
+	sign := ""
+	if v.Sign() < 0 {
+		sign, s = "-", s[1:]
+	}
+
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + frac
+}
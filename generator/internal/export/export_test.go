@@ -0,0 +1,66 @@
+package export
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+)
+
+const account = "0xAccount"
+
+// transitions returns the history of a pending transaction moved through
+// each of the given states an hour apart, the first one at at
+func transitions(at time.Time, to ...domain.TransactionState) []domain.Transition {
+	trs := []domain.Transition{
+		{To: domain.TransactionInitiated, At: at.Add(-24 * time.Hour)},
+		{From: domain.TransactionInitiated, To: domain.TransactionPending, At: at.Add(-time.Hour)},
+	}
+
+	from := domain.TransactionPending
+	for i, s := range to {
+		trs = append(trs, domain.Transition{From: from, To: s, At: at.Add(time.Duration(i) * time.Hour)})
+		from = s
+	}
+
+	return trs
+}
+
+func transactions() []domain.Transaction {
+	at := time.Date(2021, 9, 1, 12, 30, 0, 0, time.UTC)
+
+	return []domain.Transaction{
+		{
+			ID:          "1",
+			From:        "0xother",
+			To:          "0xaccount",
+			Value:       new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17)),
+			State:       domain.TransactionSettled,
+			Transitions: transitions(at, domain.TransactionSettled),
+		},
+		{
+			ID:          "2",
+			From:        account,
+			To:          "0xother",
+			Value:       big.NewInt(1),
+			State:       domain.TransactionReversed,
+			Transitions: transitions(at.Add(24*time.Hour), domain.TransactionSettled, domain.TransactionReversed),
+		},
+		{
+			ID:          "3",
+			From:        account,
+			To:          "0xother",
+			Value:       big.NewInt(2),
+			State:       domain.TransactionFailed,
+			Transitions: transitions(at, domain.TransactionFailed),
+		},
+		{
+			ID:          "4",
+			From:        account,
+			To:          "0xother",
+			Value:       big.NewInt(3),
+			State:       domain.TransactionPending,
+			Transitions: transitions(at),
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+)
+
+const ofxDate = "20060102150405"
+
+// Escapes the characters SGML would read as markup
+var ofxEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+const ofxHeader = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>0
+<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS>
+<CURDEF>BNB
+<BANKACCTFROM><BANKID>BSC<ACCTID>%s<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>%s
+<DTEND>%s
+`
+
+const ofxFooter = `</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+type ofx struct {
+	w        io.Writer
+	account  string
+	from, to time.Time
+	started  bool
+}
+
+// NewOFX creates an exporter writing an OFX 1.02 bank statement of account
+// for the period between from and to, leaving out entries dated outside it
+func NewOFX(w io.Writer, account string, from, to time.Time) Exporter {
+	return &ofx{
+		w:       w,
+		account: account,
+		from:    from,
+		to:      to,
+	}
+}
+
+func (o *ofx) start() error {
+	if o.started {
+		return nil
+	}
+	o.started = true
+
+	_, err := fmt.Fprintf(o.w, ofxHeader, ofxEscaper.Replace(o.account), o.from.UTC().Format(ofxDate), o.to.UTC().Format(ofxDate))
+	return err
+}
+
+func (o *ofx) Write(tx domain.Transaction) error {
+	for _, e := range entries(tx, o.account) {
+		if e.at.Before(o.from) || e.at.After(o.to) {
+			continue
+		}
+
+		if err := o.start(); err != nil {
+			return err
+		}
+
+		kind := "CREDIT"
+		if e.value.Sign() < 0 {
+			kind = "DEBIT"
+		}
+
+		_, err := fmt.Fprintf(o.w, "<STMTTRN><TRNTYPE>%s<DTPOSTED>%s<TRNAMT>%s<FITID>%s<MEMO>%s</STMTTRN>\n",
+			kind,
+			e.at.Format(ofxDate),
+			amount(e.value),
+			ofxEscaper.Replace(e.id),
+			ofxEscaper.Replace(e.counterparty),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *ofx) Close() error {
+	if err := o.start(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(o.w, ofxFooter)
+	return err
+}
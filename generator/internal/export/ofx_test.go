@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the OFX statement of a wallet
+func TestOFX(t *testing.T) {
+	b := &bytes.Buffer{}
+	from := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	e := NewOFX(b, account, from, from.AddDate(0, 1, 0))
+	for _, tx := range transactions() {
+		assert.NoError(t, e.Write(tx))
+	}
+	assert.NoError(t, e.Close())
+
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, "OFXHEADER:100\n"))
+	assert.Contains(t, out, "<ACCTID>0xAccount<ACCTTYPE>CHECKING")
+	assert.Contains(t, out, "<DTSTART>20210901000000\n<DTEND>20211001000000\n")
+	assert.Contains(t, out, "<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20210901123000<TRNAMT>1.5<FITID>1<MEMO>0xother</STMTTRN>\n")
+	assert.Contains(t, out, "<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20210902123000<TRNAMT>-0.000000000000000001<FITID>2<MEMO>0xother</STMTTRN>\n"+
+		"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20210902133000<TRNAMT>0.000000000000000001<FITID>2-reversal<MEMO>0xother</STMTTRN>\n")
+	assert.NotContains(t, out, "<FITID>3")
+	assert.NotContains(t, out, "<FITID>4")
+	assert.True(t, strings.HasSuffix(out, "</BANKTRANLIST>\n</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n"))
+}
+
+// Test that markup characters are escaped
+func TestOFXEscape(t *testing.T) {
+	b := &bytes.Buffer{}
+	tx := transactions()[0]
+	tx.ID, tx.From = "a<b", "R&D"
+
+	e := NewOFX(b, "<acct>", time.Time{}, time.Now())
+	assert.NoError(t, e.Write(tx))
+	assert.NoError(t, e.Close())
+
+	assert.Contains(t, b.String(), "<ACCTID>&lt;acct&gt;<ACCTTYPE>")
+	assert.Contains(t, b.String(), "<FITID>a&lt;b<MEMO>R&amp;D</STMTTRN>")
+}
+
+// Test that entries outside the period are left out of the statement
+func TestOFXPeriod(t *testing.T) {
+	b := &bytes.Buffer{}
+	from := time.Date(2021, 9, 2, 13, 0, 0, 0, time.UTC)
+
+	e := NewOFX(b, account, from, from.AddDate(0, 1, 0))
+	for _, tx := range transactions() {
+		assert.NoError(t, e.Write(tx))
+	}
+	assert.NoError(t, e.Close())
+
+	out := b.String()
+	assert.NotContains(t, out, "<FITID>1<")
+	assert.NotContains(t, out, "<FITID>2<")
+	assert.Contains(t, out, "<FITID>2-reversal<")
+}
+
+// Test that an empty OFX statement is still well formed
+func TestOFXEmpty(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	assert.NoError(t, NewOFX(b, account, time.Time{}, time.Time{}).Close())
+	assert.Contains(t, b.String(), "<BANKTRANLIST>\n<DTSTART>00010101000000\n<DTEND>00010101000000\n</BANKTRANLIST>")
+}
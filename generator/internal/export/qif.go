@@ -0,0 +1,58 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+)
+
+const qifDate = "01/02/2006"
+
+type qif struct {
+	w       io.Writer
+	account string
+	started bool
+}
+
+// NewQIF creates an exporter writing a QIF bank register of account
+func NewQIF(w io.Writer, account string) Exporter {
+	return &qif{
+		w:       w,
+		account: account,
+	}
+}
+
+func (q *qif) start() error {
+	if q.started {
+		return nil
+	}
+	q.started = true
+
+	_, err := io.WriteString(q.w, "!Type:Bank\n")
+	return err
+}
+
+func (q *qif) Write(tx domain.Transaction) error {
+	for _, e := range entries(tx, q.account) {
+		if err := q.start(); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprintf(q.w, "D%s\nT%s\nN%s\nP%s\n^\n",
+			e.at.Format(qifDate),
+			amount(e.value),
+			e.id,
+			e.counterparty,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *qif) Close() error {
+	return q.start()
+}
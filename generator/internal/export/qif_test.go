@@ -0,0 +1,33 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the QIF register of a wallet
+func TestQIF(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	e := NewQIF(b, account)
+	for _, tx := range transactions() {
+		assert.NoError(t, e.Write(tx))
+	}
+	assert.NoError(t, e.Close())
+
+	expected := "!Type:Bank\n" +
+		"D09/01/2021\nT1.5\nN1\nP0xother\n^\n" +
+		"D09/02/2021\nT-0.000000000000000001\nN2\nP0xother\n^\n" +
+		"D09/02/2021\nT0.000000000000000001\nN2-reversal\nP0xother\n^\n"
+	assert.Equal(t, expected, b.String())
+}
+
+// Test that an empty QIF register only has its header
+func TestQIFEmpty(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	assert.NoError(t, NewQIF(b, account).Close())
+	assert.Equal(t, "!Type:Bank\n", b.String())
+}
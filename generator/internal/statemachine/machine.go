@@ -0,0 +1,153 @@
+package statemachine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type State string
+
+// Guard decides whether subject, the entity moving between states, may make a
+// transition, returning an error to block it
+type Guard func(subject interface{}, from, to State) error
+
+// Timeout moves a state to another one once it has been held for After
+type Timeout struct {
+	After time.Duration
+	To    State
+}
+
+type Machine struct {
+	initial     State
+	transitions map[State]map[State]Guard
+	timeouts    map[State]Timeout
+}
+
+// New creates a machine starting at the given state
+func New(initial State) *Machine {
+	return &Machine{
+		initial:     initial,
+		transitions: map[State]map[State]Guard{},
+		timeouts:    map[State]Timeout{},
+	}
+}
+
+// Initial returns the state every machine instance starts at
+func (m *Machine) Initial() State {
+	return m.initial
+}
+
+// Allow registers the transitions from a state to each of the given ones,
+// keeping the guards of those already allowed
+func (m *Machine) Allow(from State, to ...State) *Machine {
+	if m.transitions[from] == nil {
+		m.transitions[from] = map[State]Guard{}
+	}
+
+	for _, s := range to {
+		if _, ok := m.transitions[from][s]; !ok {
+			m.transitions[from][s] = nil
+		}
+	}
+
+	return m
+}
+
+// Guard attaches a guard to an already allowed transition
+func (m *Machine) Guard(from, to State, g Guard) *Machine {
+	if _, ok := m.transitions[from][to]; !ok {
+		panic(fmt.Sprintf("statemachine: guard on unknown transition from %s to %s", from, to))
+	}
+
+	m.transitions[from][to] = g
+
+	return m
+}
+
+// Timeout registers an automatic transition out of a state
+func (m *Machine) Timeout(from State, after time.Duration, to State) *Machine {
+	if _, ok := m.transitions[from][to]; !ok {
+		panic(fmt.Sprintf("statemachine: timeout on unknown transition from %s to %s", from, to))
+	}
+
+	m.timeouts[from] = Timeout{
+		After: after,
+		To:    to,
+	}
+
+	return m
+}
+
+// Can reports whether the transition is allowed, without running its guard
+func (m *Machine) Can(from, to State) bool {
+	_, ok := m.transitions[from][to]
+	return ok
+}
+
+// Transition validates a transition of subject and runs its guard
+func (m *Machine) Transition(subject interface{}, from, to State) error {
+	g, ok := m.transitions[from][to]
+	if !ok {
+		return fmt.Errorf("invalid transition from %s to %s", from, to)
+	}
+
+	if g == nil {
+		return nil
+	}
+
+	return g(subject, from, to)
+}
+
+// Expired returns the state to move to when a state entered at since has
+// timed out by now
+func (m *Machine) Expired(state State, since, now time.Time) (State, bool) {
+	t, ok := m.timeouts[state]
+	if !ok || now.Sub(since) < t.After {
+		return "", false
+	}
+
+	return t.To, true
+}
+
+// DOT exports the machine as a graphviz digraph
+func (m *Machine) DOT(name string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	fmt.Fprintf(&b, "\t%q [shape=doublecircle];\n", m.initial)
+
+	for _, from := range sortedStates(m.transitions) {
+		to := make([]string, 0, len(m.transitions[from]))
+		for s := range m.transitions[from] {
+			to = append(to, string(s))
+		}
+		sort.Strings(to)
+
+		for _, s := range to {
+			label := ""
+			if t, ok := m.timeouts[from]; ok && t.To == State(s) {
+				label = fmt.Sprintf(" [label=%q]", "after "+t.After.String())
+			}
+			fmt.Fprintf(&b, "\t%q -> %q%s;\n", from, s, label)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func sortedStates(m map[State]map[State]Guard) []State {
+	states := make([]State, 0, len(m))
+	for s := range m {
+		states = append(states, s)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i] < states[j]
+	})
+
+	return states
+}
@@ -0,0 +1,73 @@
+package statemachine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMachine() *Machine {
+	return New("a").
+		Allow("a", "b", "c").
+		Allow("b", "c").
+		Timeout("b", time.Minute, "c")
+}
+
+// Test allowed and rejected transitions
+func TestTransition(t *testing.T) {
+	m := newMachine()
+
+	assert.Equal(t, State("a"), m.Initial())
+	assert.NoError(t, m.Transition(nil, "a", "b"))
+	assert.True(t, m.Can("b", "c"))
+	assert.Error(t, m.Transition(nil, "c", "a"))
+}
+
+// Test that a guard can block a transition
+func TestGuard(t *testing.T) {
+	m := newMachine().Guard("a", "c", func(subject interface{}, from, to State) error {
+		if subject.(int) > 10 {
+			return fmt.Errorf("blocked")
+		}
+		return nil
+	})
+
+	assert.Error(t, m.Transition(11, "a", "c"))
+	assert.NoError(t, m.Transition(1, "a", "c"))
+	assert.NoError(t, m.Transition(11, "a", "b"))
+	assert.Panics(t, func() { m.Guard("c", "a", nil) })
+
+	// Allowing a transition again keeps its guard
+	m.Allow("a", "c")
+	assert.Error(t, m.Transition(11, "a", "c"))
+}
+
+// Test timed out states
+func TestExpired(t *testing.T) {
+	m := newMachine()
+	since := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := m.Expired("b", since, since.Add(time.Second))
+	assert.False(t, ok)
+
+	s, ok := m.Expired("b", since, since.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, State("c"), s)
+
+	_, ok = m.Expired("a", since, since.Add(time.Hour))
+	assert.False(t, ok)
+}
+
+// Test the graphviz export
+func TestDOT(t *testing.T) {
+	expected := `digraph "test" {
+	"a" [shape=doublecircle];
+	"a" -> "b";
+	"a" -> "c";
+	"b" -> "c" [label="after 1m0s"];
+}
+`
+	assert.Equal(t, expected, newMachine().DOT("test"))
+}
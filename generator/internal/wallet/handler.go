@@ -1,9 +1,12 @@
 package wallet
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/ezegrosfeld/wallet/generator/pkg/jwe"
+	"github.com/ezegrosfeld/wallet/generator/pkg/logging"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -11,24 +14,30 @@ import (
 type Handler interface {
 	Create() gin.HandlerFunc
 	Get() gin.HandlerFunc
+	Derive() gin.HandlerFunc
 }
 
 type handler struct {
-	s   Service
-	log *zap.SugaredLogger
+	s    Service
+	log  *zap.SugaredLogger
+	keys *jwe.KeySet
 }
 
-func NewHandler(s Service, log *zap.SugaredLogger) Handler {
+// NewHandler creates the wallet handlers, keys decrypting the seeds sent in a
+// JWE envelope. Envelopes are rejected when keys is nil.
+func NewHandler(s Service, log *zap.SugaredLogger, keys *jwe.KeySet) Handler {
 	return &handler{
-		s:   s,
-		log: log,
+		s:    s,
+		log:  log,
+		keys: keys,
 	}
 }
 
 func (h *handler) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		wallet, err := h.s.Create()
+		wallet, err := h.s.Create(c.Request.Context())
 		if err != nil {
+			logging.FromContext(c.Request.Context(), h.log).Errorw("could not create wallet", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
 			})
@@ -57,8 +66,15 @@ func (h *handler) Get() gin.HandlerFunc {
 			return
 		}
 
-		wallet, err := h.s.Get(seed, int(i))
+		wallet, err := h.s.Get(c.Request.Context(), seed, int(i))
+		if errors.Is(err, ErrInvalidIndex) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		if err != nil {
+			logging.FromContext(c.Request.Context(), h.log).Errorw("could not derive wallet", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
 			})
@@ -69,3 +85,60 @@ func (h *handler) Get() gin.HandlerFunc {
 
 	}
 }
+
+type deriveRequest struct {
+	Seed    string `json:"seed" binding:"required_without=SeedJWE,excluded_with=SeedJWE"`
+	SeedJWE string `json:"seed_jwe"`                             // Seed sealed in a JWE compact token
+	Index   int    `json:"index" binding:"min=0,max=2147483647"` // Indexes from 2^31 are hardened
+}
+
+// Derive works like Get but takes the seed in the request body, keeping it
+// out of URLs and access logs. The seed may be sent in a JWE envelope so it
+// is not readable by proxies terminating TLS.
+func (h *handler) Derive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := deriveRequest{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		if req.SeedJWE != "" {
+			if h.keys == nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "encrypted seeds are not accepted",
+				})
+				return
+			}
+
+			seed, err := h.keys.Decrypt(req.SeedJWE)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			req.Seed = string(seed)
+		}
+
+		wallet, err := h.s.Get(c.Request.Context(), req.Seed, req.Index)
+		if errors.Is(err, ErrInvalidIndex) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			logging.FromContext(c.Request.Context(), h.log).Errorw("could not derive wallet", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, wallet)
+	}
+}
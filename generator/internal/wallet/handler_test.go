@@ -2,6 +2,9 @@ package wallet
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+	"github.com/ezegrosfeld/wallet/generator/pkg/jwe"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,24 +23,30 @@ type mockedService struct {
 	mock.Mock
 }
 
-func (m *mockedService) Create() (domain.Wallet, error) {
+func (m *mockedService) Create(ctx context.Context) (domain.Wallet, error) {
 	args := m.Called()
 	return args.Get(0).(domain.Wallet), args.Error(1)
 }
 
-func (m *mockedService) Get(seedString string, index int) (domain.Wallet, error) {
+func (m *mockedService) Get(ctx context.Context, seedString string, index int) (domain.Wallet, error) {
 	args := m.Called(seedString, index)
 	return args.Get(0).(domain.Wallet), args.Error(1)
 }
 
 // create a mocked service for testing
 func createMockedService(s *mockedService) *gin.Engine {
-	handler := NewHandler(s, &zap.SugaredLogger{})
+	return createMockedServiceWithKeys(s, nil)
+}
+
+// create a mocked service accepting seeds sealed for keys
+func createMockedServiceWithKeys(s *mockedService, keys *jwe.KeySet) *gin.Engine {
+	handler := NewHandler(s, zap.NewNop().Sugar(), keys)
 
 	router := gin.Default()
 
 	router.POST("/wallet", handler.Create())
 	router.GET("/wallet", handler.Get())
+	router.POST("/wallet/derive", handler.Derive())
 
 	return router
 }
@@ -129,6 +139,20 @@ func TestGetErrorSeed(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rw.Code)
 }
 
+// Test Get with an index rejected by the service
+func TestGetErrorIndex(t *testing.T) {
+	s := new(mockedService)
+	s.On("Get", "seed", -1).Return(domain.Wallet{}, ErrInvalidIndex)
+
+	router := createMockedService(s)
+	req, rw := createRequest("GET", "/wallet?seed=seed&index=-1", "")
+
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"error"`)
+}
+
 // Test get with error in service
 func TestGetErrorService(t *testing.T) {
 	s := new(mockedService)
@@ -141,3 +165,102 @@ func TestGetErrorService(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, rw.Code)
 }
+
+// Test Derive
+func TestDerive(t *testing.T) {
+	type response struct {
+		Address string `json:"address"`
+		Index   int    `json:"index"`
+	}
+
+	s := new(mockedService)
+	s.On("Get", "seed", 1).Return(domain.Wallet{
+		Index:   1,
+		Address: "address",
+	}, nil)
+
+	router := createMockedService(s)
+	req, rw := createRequest("POST", "/wallet/derive", `{"seed": "seed", "index": 1}`)
+
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	res := response{}
+
+	err := json.Unmarshal(rw.Body.Bytes(), &res)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "address", res.Address)
+	assert.Equal(t, 1, res.Index)
+}
+
+// Test Derive with an invalid body
+func TestDeriveErrorBody(t *testing.T) {
+	s := new(mockedService)
+	router := createMockedService(s)
+
+	for _, body := range []string{
+		"",
+		`{"index": 0}`,
+		`{"seed": "seed", "index": -1}`,
+		`{"seed": "seed", "index": 2147483648}`,
+		`{"seed": "seed", "index": 5000000000}`,
+	} {
+		req, rw := createRequest("POST", "/wallet/derive", body)
+
+		router.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code, body)
+		assert.Contains(t, rw.Body.String(), `"error"`, body)
+	}
+}
+
+// Test Derive with the seed in a JWE envelope
+func TestDeriveEncrypted(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	token, err := jwe.Encrypt(&key.PublicKey, "current", []byte("seed"))
+	assert.NoError(t, err)
+
+	s := new(mockedService)
+	s.On("Get", "seed", 1).Return(domain.Wallet{Index: 1, Address: "address"}, nil)
+
+	router := createMockedServiceWithKeys(s, jwe.NewKeySet(map[string]*rsa.PrivateKey{"current": key}))
+
+	req, rw := createRequest("POST", "/wallet/derive", `{"seed_jwe": "`+token+`", "index": 1}`)
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"address"`)
+
+	for _, body := range []string{
+		`{"seed_jwe": "a.b.c.d.e", "index": 1}`,
+		`{"seed": "seed", "seed_jwe": "` + token + `", "index": 1}`,
+	} {
+		req, rw = createRequest("POST", "/wallet/derive", body)
+		router.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code, body)
+	}
+
+	// Envelopes are rejected without keys to open them
+	req, rw = createRequest("POST", "/wallet/derive", `{"seed_jwe": "`+token+`", "index": 1}`)
+	createMockedService(s).ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
+
+// Test Derive with error in service
+func TestDeriveErrorService(t *testing.T) {
+	s := new(mockedService)
+	s.On("Get", "seed", 0).Return(domain.Wallet{}, fmt.Errorf("An error ocurred while deriving the wallet"))
+
+	router := createMockedService(s)
+	req, rw := createRequest("POST", "/wallet/derive", `{"seed": "seed"}`)
+
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+}
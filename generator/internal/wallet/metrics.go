@@ -0,0 +1,23 @@
+package wallet
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	walletsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "generator_wallets_created_total",
+		Help: "Number of wallets generated.",
+	})
+
+	addressesDerived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "generator_addresses_derived_total",
+		Help: "Number of addresses derived from an existing seed.",
+	})
+
+	serviceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "generator_errors_total",
+		Help: "Number of failed wallet operations by operation.",
+	}, []string{"operation"})
+)
@@ -1,10 +1,14 @@
 package wallet
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/ezegrosfeld/wallet/generator/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 
 	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
@@ -12,10 +16,20 @@ import (
 )
 
 type Service interface {
-	Create() (domain.Wallet, error)
-	Get(seedString string, index int) (domain.Wallet, error)
+	Create(ctx context.Context) (domain.Wallet, error)
+	Get(ctx context.Context, seedString string, index int) (domain.Wallet, error)
 }
 
+// MaxIndex is the highest index an address is derived at, indexes from 2^31
+// derive hardened children
+const MaxIndex = 1<<31 - 1
+
+// ErrInvalidIndex is returned when an address is asked for at an index out of
+// the 0 to MaxIndex range
+var ErrInvalidIndex = fmt.Errorf("index must be between 0 and %d", MaxIndex)
+
+var tracer = otel.Tracer("github.com/ezegrosfeld/wallet/generator/internal/wallet")
+
 type service struct {
 	log *zap.SugaredLogger
 }
@@ -26,7 +40,24 @@ func NewService(log *zap.SugaredLogger) Service {
 	}
 }
 
-func (s *service) Create() (domain.Wallet, error) {
+func (s *service) Create(ctx context.Context) (domain.Wallet, error) {
+	_, span := tracer.Start(ctx, "wallet.Create")
+	defer span.End()
+
+	w, err := s.create()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		serviceErrors.WithLabelValues("create").Inc()
+		return w, err
+	}
+
+	walletsCreated.Inc()
+
+	return w, nil
+}
+
+func (s *service) create() (domain.Wallet, error) {
 	entropy, err := bip39.NewEntropy(128)
 	if err != nil {
 		return domain.Wallet{}, err
@@ -59,7 +90,35 @@ func (s *service) Create() (domain.Wallet, error) {
 	return w, nil
 }
 
-func (s *service) Get(seedString string, index int) (domain.Wallet, error) {
+func (s *service) Get(ctx context.Context, seedString string, index int) (domain.Wallet, error) {
+	_, span := tracer.Start(ctx, "wallet.Get")
+	defer span.End()
+
+	// Never record the seed on the span
+	span.SetAttributes(attribute.Int("wallet.index", index))
+
+	w, err := s.get(seedString, index)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		serviceErrors.WithLabelValues("get").Inc()
+		return w, err
+	}
+
+	addressesDerived.Inc()
+
+	return w, nil
+}
+
+func (s *service) get(seedString string, index int) (domain.Wallet, error) {
+	if index < 0 || index > MaxIndex {
+		return domain.Wallet{}, ErrInvalidIndex
+	}
+
+	path, err := hdwallet.ParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", index))
+	if err != nil {
+		return domain.Wallet{}, err
+	}
 
 	pwd := os.Getenv("WALLET_PASSWORD")
 
@@ -70,8 +129,6 @@ func (s *service) Get(seedString string, index int) (domain.Wallet, error) {
 		return domain.Wallet{}, err
 	}
 
-	path := hdwallet.MustParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", index))
-
 	account, err := wallet.Derive(path, false)
 	if err != nil {
 		return domain.Wallet{}, err
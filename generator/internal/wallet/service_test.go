@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -12,7 +13,7 @@ import (
 func TestWalletCreation(t *testing.T) {
 	service := NewService(&zap.SugaredLogger{})
 
-	wallet, err := service.Create()
+	wallet, err := service.Create(context.Background())
 	assert.NoError(t, err)
 	assert.NotNil(t, wallet.Address)
 	assert.NotNil(t, wallet.Seed)
@@ -21,7 +22,7 @@ func TestWalletCreation(t *testing.T) {
 	assert.Equal(t, 12, len(strings.Split(wallet.Seed, " ")))
 
 	// Check uniqueness of the address and seed
-	wallet2, err := service.Create()
+	wallet2, err := service.Create(context.Background())
 	assert.NoError(t, err)
 
 	assert.NotEqual(t, wallet.Address, wallet2.Address)
@@ -33,11 +34,27 @@ func TestWalletCreation(t *testing.T) {
 func TestWalletGet(t *testing.T) {
 	service := NewService(&zap.SugaredLogger{})
 
-	wallet, err := service.Create()
+	wallet, err := service.Create(context.Background())
 	assert.NoError(t, err)
 
-	wallet2, err := service.Get(wallet.Seed, 0)
+	wallet2, err := service.Get(context.Background(), wallet.Seed, 0)
 	assert.NoError(t, err)
 
 	assert.Equal(t, wallet.Address, wallet2.Address)
 }
+
+// Test that indexes out of the non-hardened range are rejected
+func TestWalletGetInvalidIndex(t *testing.T) {
+	service := NewService(&zap.SugaredLogger{})
+
+	wallet, err := service.Create(context.Background())
+	assert.NoError(t, err)
+
+	for _, index := range []int{-1, MaxIndex + 1} {
+		_, err = service.Get(context.Background(), wallet.Seed, index)
+		assert.ErrorIs(t, err, ErrInvalidIndex, index)
+	}
+
+	_, err = service.Get(context.Background(), wallet.Seed, MaxIndex)
+	assert.NoError(t, err)
+}
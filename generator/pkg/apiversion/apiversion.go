@@ -0,0 +1,44 @@
+package apiversion
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version describes a version of an API served under its own prefix
+type Version struct {
+	Name       string
+	Deprecated bool
+	Sunset     time.Time // Date the version stops being served, if known
+	Successor  string    // Prefix clients should migrate to
+}
+
+// Middleware tags every response with the version serving it and, for
+// deprecated versions, the standard deprecation headers
+func (v Version) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("API-Version", v.Name)
+
+		if v.Deprecated {
+			c.Header("Deprecation", "true")
+
+			if !v.Sunset.IsZero() {
+				c.Header("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if v.Successor != "" {
+				c.Header("Link", "<"+v.Successor+">; rel=\"successor-version\"")
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Group creates a router group for the version under prefix, running
+// handlers after the version middleware
+func (v Version) Group(r gin.IRouter, prefix string, handlers ...gin.HandlerFunc) *gin.RouterGroup {
+	return r.Group(prefix, append([]gin.HandlerFunc{v.Middleware()}, handlers...)...)
+}
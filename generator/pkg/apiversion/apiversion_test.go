@@ -0,0 +1,46 @@
+package apiversion
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(v Version) *httptest.ResponseRecorder {
+	router := gin.Default()
+
+	v.Group(router, "/test").GET("/", func(c *gin.Context) {
+		c.String(200, "OK")
+	})
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("GET", "/test/", nil))
+
+	return rw
+}
+
+// Test the headers of a current version
+func TestVersion(t *testing.T) {
+	rw := serve(Version{Name: "v1"})
+
+	assert.Equal(t, "v1", rw.Header().Get("API-Version"))
+	assert.Empty(t, rw.Header().Get("Deprecation"))
+}
+
+// Test the headers of a deprecated version
+func TestDeprecatedVersion(t *testing.T) {
+	rw := serve(Version{
+		Name:       "v0",
+		Deprecated: true,
+		Sunset:     time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+		Successor:  "/v1",
+	})
+
+	assert.Equal(t, "v0", rw.Header().Get("API-Version"))
+	assert.Equal(t, "true", rw.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", rw.Header().Get("Sunset"))
+	assert.Equal(t, "</v1>; rel=\"successor-version\"", rw.Header().Get("Link"))
+}
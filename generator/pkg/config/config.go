@@ -0,0 +1,265 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Flag and variable selecting the YAML file to load
+const (
+	fileFlag = "config"
+	fileEnv  = "CONFIG_FILE"
+)
+
+// Variable naming the environment, whose overrides are loaded from
+// <file>.<environment>.yaml next to the config file
+const environmentEnv = "ENVIRONMENT"
+
+// Lookup of environment variables, replaced in tests
+var lookupEnv = os.LookupEnv
+
+// Validator is implemented by configs needing checks beyond required fields
+type Validator interface {
+	Validate() error
+}
+
+// Load fills the struct pointed to by cfg, each source overriding the
+// previous one:
+//
+//   - the default tag of each field
+//   - the YAML file given by -config or CONFIG_FILE, using the yaml tags
+//   - its per-environment override, when ENVIRONMENT is set
+//   - the variables named by the env tags
+//   - the flags named by the flag tags, parsed from args
+//
+// Fields tagged required:"true" must end up with a non zero value.
+func Load(cfg interface{}, args []string) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: expected a pointer to a struct, got %T", cfg)
+	}
+
+	fields := collect(v.Elem())
+
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	file := fs.String(fileFlag, "", "YAML file to load the configuration from")
+	for _, f := range fields {
+		if f.flag != "" {
+			fs.Var(&flagValue{value: f.def, bool: f.value.Kind() == reflect.Bool}, f.flag, f.usage)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := f.set(f.def); err != nil {
+			return fmt.Errorf("config: default of %s: %w", f.name, err)
+		}
+	}
+
+	if *file == "" {
+		*file, _ = lookupEnv(fileEnv)
+	}
+
+	if *file != "" {
+		if err := loadFile(cfg, *file, false); err != nil {
+			return err
+		}
+
+		if env, _ := lookupEnv(environmentEnv); env != "" {
+			ext := filepath.Ext(*file)
+			override := strings.TrimSuffix(*file, ext) + "." + env + ext
+			if err := loadFile(cfg, override, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if s, ok := lookupEnv(f.env); ok {
+			if err := f.set(s); err != nil {
+				return fmt.Errorf("config: %s: %w", f.env, err)
+			}
+		}
+	}
+
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if err == nil && f.flag == fl.Name {
+				if serr := f.set(fl.Value.String()); serr != nil {
+					err = fmt.Errorf("config: -%s: %w", f.flag, serr)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, f := range fields {
+		if f.required && f.value.IsZero() {
+			missing = append(missing, f.describe())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("config: missing required %s", strings.Join(missing, ", "))
+	}
+
+	if val, ok := cfg.(Validator); ok {
+		return val.Validate()
+	}
+
+	return nil
+}
+
+func loadFile(cfg interface{}, path string, optional bool) error {
+	b, err := ioutil.ReadFile(path)
+	if optional && os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(b, cfg); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// flagValue holds the raw value of a flag until it is applied to its field
+type flagValue struct {
+	value string
+	bool  bool
+}
+
+func (v *flagValue) String() string {
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.bool
+}
+
+type field struct {
+	name     string
+	value    reflect.Value
+	env      string
+	flag     string
+	def      string
+	usage    string
+	required bool
+}
+
+// collect returns the fields of a struct, descending into nested structs
+func collect(v reflect.Value) []field {
+	var fields []field
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Time{}) {
+			fields = append(fields, collect(fv)...)
+			continue
+		}
+
+		fields = append(fields, field{
+			name:     sf.Name,
+			value:    fv,
+			env:      sf.Tag.Get("env"),
+			flag:     sf.Tag.Get("flag"),
+			def:      sf.Tag.Get("default"),
+			usage:    sf.Tag.Get("usage"),
+			required: sf.Tag.Get("required") == "true",
+		})
+	}
+
+	return fields
+}
+
+func (f field) describe() string {
+	var sources []string
+	if f.env != "" {
+		sources = append(sources, f.env)
+	}
+	if f.flag != "" {
+		sources = append(sources, "-"+f.flag)
+	}
+
+	if len(sources) == 0 {
+		return f.name
+	}
+
+	return f.name + " (" + strings.Join(sources, " or ") + ")"
+}
+
+func (f field) set(s string) error {
+	switch {
+	case f.value.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(s)
+	case f.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(b)
+	case f.value.Kind() >= reflect.Int && f.value.Kind() <= reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, f.value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(i)
+	case f.value.Kind() >= reflect.Uint && f.value.Kind() <= reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, f.value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.value.SetUint(u)
+	case f.value.Kind() == reflect.Float64 || f.value.Kind() == reflect.Float32:
+		n, err := strconv.ParseFloat(s, f.value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.value.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type database struct {
+	URL string `yaml:"url" env:"DATABASE_URL" required:"true"`
+}
+
+type testConfig struct {
+	Addr     string        `yaml:"addr" env:"ADDR" flag:"addr" default:":8080"`
+	Timeout  time.Duration `yaml:"timeout" env:"TIMEOUT" flag:"timeout" default:"10s"`
+	Workers  int           `yaml:"workers" env:"WORKERS" default:"1"`
+	Debug    bool          `yaml:"debug" flag:"debug"`
+	Database database      `yaml:"database"`
+}
+
+type validatedConfig struct {
+	Workers int `env:"WORKERS"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("workers must not be negative")
+	}
+	return nil
+}
+
+func withEnv(t *testing.T, env map[string]string) {
+	lookupEnv = func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+	t.Cleanup(func() { lookupEnv = os.LookupEnv })
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// Test that defaults apply when nothing else is set
+func TestLoadDefaults(t *testing.T) {
+	withEnv(t, map[string]string{"DATABASE_URL": "postgres://"})
+
+	cfg := testConfig{}
+	assert.NoError(t, Load(&cfg, nil))
+
+	assert.Equal(t, ":8080", cfg.Addr)
+	assert.Equal(t, 10*time.Second, cfg.Timeout)
+	assert.Equal(t, 1, cfg.Workers)
+	assert.False(t, cfg.Debug)
+	assert.Equal(t, "postgres://", cfg.Database.URL)
+}
+
+// Test the precedence of file, environment override, variables and flags
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "config.yaml", "addr: :9000\ntimeout: 1m\nworkers: 2\ndatabase:\n  url: file\n")
+	writeFile(t, dir, "config.production.yaml", "workers: 3\ndatabase:\n  url: production\n")
+
+	withEnv(t, map[string]string{
+		"CONFIG_FILE": file,
+		"ENVIRONMENT": "production",
+		"TIMEOUT":     "2m",
+	})
+
+	cfg := testConfig{}
+	assert.NoError(t, Load(&cfg, []string{"-timeout", "3m", "-debug"}))
+
+	assert.Equal(t, ":9000", cfg.Addr)
+	assert.Equal(t, 3*time.Minute, cfg.Timeout)
+	assert.Equal(t, 3, cfg.Workers)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, "production", cfg.Database.URL)
+}
+
+// Test that the config file can be given as a flag and must exist
+func TestLoadFileFlag(t *testing.T) {
+	withEnv(t, map[string]string{})
+
+	cfg := testConfig{}
+	assert.Error(t, Load(&cfg, []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}))
+
+	file := writeFile(t, t.TempDir(), "config.yaml", "unknown: true\n")
+	assert.Error(t, Load(&cfg, []string{"-config", file}))
+}
+
+// Test that missing required fields are reported
+func TestLoadRequired(t *testing.T) {
+	withEnv(t, map[string]string{})
+
+	err := Load(&testConfig{}, nil)
+	assert.EqualError(t, err, "config: missing required URL (DATABASE_URL)")
+}
+
+// Test that invalid values are rejected
+func TestLoadInvalid(t *testing.T) {
+	withEnv(t, map[string]string{"DATABASE_URL": "postgres://", "WORKERS": "many"})
+
+	assert.Error(t, Load(&testConfig{}, nil))
+	assert.Error(t, Load(testConfig{}, nil))
+
+	withEnv(t, map[string]string{"DATABASE_URL": "postgres://"})
+	assert.Error(t, Load(&testConfig{}, []string{"-timeout", "soon"}))
+	assert.Error(t, Load(&testConfig{}, []string{"-unknown"}))
+}
+
+// Test that configs can validate themselves
+func TestLoadValidator(t *testing.T) {
+	withEnv(t, map[string]string{"WORKERS": "-1"})
+
+	assert.EqualError(t, Load(&validatedConfig{}, nil), "workers must not be negative")
+}
@@ -0,0 +1,161 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// Flag turns a feature on for everyone, for listed subjects or for a share
+// of subjects. A disabled flag is off for everyone, listed subjects included.
+type Flag struct {
+	Name       string   `json:"name"`
+	Enabled    bool     `json:"enabled"`
+	Percentage *int     `json:"percentage"`         // Share of subjects the flag is on for when enabled, nil for no rollout
+	Subjects   []string `json:"subjects,omitempty"` // Subjects the flag is on for when enabled, whatever the percentage
+}
+
+// Percent returns a percentage to roll a flag out to
+func Percent(n int) *int {
+	return &n
+}
+
+func (f Flag) validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("flag name is required")
+	}
+	if f.Percentage != nil && (*f.Percentage < 0 || *f.Percentage > 100) {
+		return fmt.Errorf("percentage of flag %s must be between 0 and 100", f.Name)
+	}
+	return nil
+}
+
+// Store holds the flags of a process, changes are not shared across replicas
+type Store struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+func NewStore() *Store {
+	return &Store{
+		flags: map[string]Flag{},
+	}
+}
+
+// Default is the store used by the package level helpers
+var Default = NewStore()
+
+// Define registers the default state of a flag, keeping it if already set
+func (s *Store) Define(f Flag) {
+	if err := f.validate(); err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.flags[f.Name]; !ok {
+		s.flags[f.Name] = f
+	}
+}
+
+// Set replaces the state of a flag
+func (s *Store) Set(f Flag) error {
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags[f.Name] = f
+
+	return nil
+}
+
+// Get returns the state of a flag
+func (s *Store) Get(name string) (Flag, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.flags[name]
+	return f, ok
+}
+
+// List returns every flag sorted by name
+func (s *Store) List() []Flag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l := make([]Flag, 0, len(s.flags))
+	for _, f := range s.flags {
+		l = append(l, f)
+	}
+
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Name < l[j].Name
+	})
+
+	return l
+}
+
+// Enabled reports whether a flag is on for the subject of ctx. An enabled
+// flag without a percentage is on for everyone, or only for its subjects if
+// it lists any. Subjects are bucketed by hash so each keeps its result while
+// the percentage grows; without a subject a partial rollout is off. Unknown
+// flags are off.
+func (s *Store) Enabled(ctx context.Context, name string) bool {
+	f, ok := s.Get(name)
+	if !ok || !f.Enabled {
+		return false
+	}
+
+	subject := SubjectFromContext(ctx)
+	for _, sub := range f.Subjects {
+		if subject != "" && subject == sub {
+			return true
+		}
+	}
+
+	if f.Percentage == nil {
+		return len(f.Subjects) == 0
+	}
+
+	if *f.Percentage >= 100 {
+		return true
+	}
+
+	if subject == "" {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + subject))
+
+	return int(h.Sum32()%100) < *f.Percentage
+}
+
+// Define registers the default state of a flag in the default store
+func Define(f Flag) {
+	Default.Define(f)
+}
+
+// Enabled reports whether a flag of the default store is on for ctx
+func Enabled(ctx context.Context, name string) bool {
+	return Default.Enabled(ctx, name)
+}
+
+type contextKey struct{}
+
+// WithSubject sets who flags are evaluated for, typically a user ID
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, contextKey{}, subject)
+}
+
+// SubjectFromContext returns who flags are evaluated for, if anyone
+func SubjectFromContext(ctx context.Context) string {
+	s, _ := ctx.Value(contextKey{}).(string)
+	return s
+}
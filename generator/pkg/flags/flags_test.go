@@ -0,0 +1,90 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test boolean flags
+func TestEnabled(t *testing.T) {
+	s := NewStore()
+	s.Define(Flag{Name: "on", Enabled: true})
+	s.Define(Flag{Name: "full", Enabled: true, Percentage: Percent(100)})
+	s.Define(Flag{Name: "none", Enabled: true, Percentage: Percent(0)})
+	s.Define(Flag{Name: "off"})
+
+	ctx := context.Background()
+	assert.True(t, s.Enabled(ctx, "on"))
+	assert.True(t, s.Enabled(ctx, "full"))
+	assert.False(t, s.Enabled(ctx, "none"))
+	assert.False(t, s.Enabled(ctx, "off"))
+	assert.False(t, s.Enabled(ctx, "unknown"))
+
+	// Defining again keeps the current state
+	s.Define(Flag{Name: "on"})
+	assert.True(t, s.Enabled(ctx, "on"))
+}
+
+// Test percentage rollouts and targeted subjects
+func TestEnabledRollout(t *testing.T) {
+	s := NewStore()
+	assert.NoError(t, s.Set(Flag{Name: "rollout", Enabled: true, Percentage: Percent(30), Subjects: []string{"beta"}}))
+
+	assert.False(t, s.Enabled(context.Background(), "rollout"))
+	assert.True(t, s.Enabled(WithSubject(context.Background(), "beta"), "rollout"))
+
+	on := 0
+	for i := 0; i < 1000; i++ {
+		if s.Enabled(WithSubject(context.Background(), fmt.Sprint(i)), "rollout") {
+			on++
+		}
+	}
+	assert.InDelta(t, 300, on, 60)
+
+	// Subjects keep their result while the rollout grows
+	ctx := WithSubject(context.Background(), "42")
+	before := s.Enabled(ctx, "rollout")
+	assert.NoError(t, s.Set(Flag{Name: "rollout", Enabled: true, Percentage: Percent(60)}))
+	assert.True(t, !before || s.Enabled(ctx, "rollout"))
+}
+
+// Test flags targeting listed subjects only
+func TestEnabledSubjects(t *testing.T) {
+	s := NewStore()
+	assert.NoError(t, s.Set(Flag{Name: "feature", Enabled: true, Subjects: []string{"beta"}}))
+
+	assert.True(t, s.Enabled(WithSubject(context.Background(), "beta"), "feature"))
+	assert.False(t, s.Enabled(WithSubject(context.Background(), "other"), "feature"))
+	assert.False(t, s.Enabled(context.Background(), "feature"))
+
+	assert.NoError(t, s.Set(Flag{Name: "feature", Enabled: true, Percentage: Percent(0), Subjects: []string{"beta"}}))
+	assert.True(t, s.Enabled(WithSubject(context.Background(), "beta"), "feature"))
+	assert.False(t, s.Enabled(WithSubject(context.Background(), "other"), "feature"))
+}
+
+// Test that disabling a flag turns it off for targeted subjects too
+func TestEnabledKillSwitch(t *testing.T) {
+	s := NewStore()
+	assert.NoError(t, s.Set(Flag{Name: "feature", Percentage: Percent(10), Subjects: []string{"beta"}}))
+
+	assert.False(t, s.Enabled(WithSubject(context.Background(), "beta"), "feature"))
+}
+
+// Test that invalid flags are rejected
+func TestSetInvalid(t *testing.T) {
+	s := NewStore()
+
+	assert.Error(t, s.Set(Flag{}))
+	assert.Error(t, s.Set(Flag{Name: "flag", Percentage: Percent(101)}))
+	assert.Panics(t, func() { s.Define(Flag{Name: "flag", Percentage: Percent(-1)}) })
+}
+
+// Test the helpers of the default store
+func TestDefault(t *testing.T) {
+	Define(Flag{Name: "default_test", Enabled: true})
+
+	assert.True(t, Enabled(context.Background(), "default_test"))
+}
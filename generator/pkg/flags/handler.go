@@ -0,0 +1,63 @@
+package flags
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require answers requests with status while the flag is off
+func Require(s *Store, name string, status int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.Enabled(c.Request.Context(), name) {
+			c.AbortWithStatusJSON(status, gin.H{
+				"error": http.StatusText(status),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RegisterAdmin maps the routes used to inspect and flip flags at runtime,
+// r must only be reachable by operators
+func RegisterAdmin(r gin.IRouter, s *Store) {
+	g := r.Group("/flags")
+
+	g.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.List())
+	})
+
+	g.GET("/:name", func(c *gin.Context) {
+		f, ok := s.Get(c.Param("name"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "flag not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, f)
+	})
+
+	g.PUT("/:name", func(c *gin.Context) {
+		f := Flag{}
+		if err := c.ShouldBindJSON(&f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		f.Name = c.Param("name")
+		if err := s.Set(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, f)
+	})
+}
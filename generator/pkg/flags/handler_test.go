@@ -0,0 +1,74 @@
+package flags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(router *gin.Engine, method, url, body string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(method, url, bytes.NewBufferString(body)))
+	return rw
+}
+
+// Test routes gated by a flag
+func TestRequire(t *testing.T) {
+	s := NewStore()
+	s.Define(Flag{Name: "feature", Enabled: true})
+
+	router := gin.Default()
+	router.GET("/feature", Require(s, "feature", http.StatusGone), func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	assert.Equal(t, http.StatusOK, serve(router, "GET", "/feature", "").Code)
+
+	assert.NoError(t, s.Set(Flag{Name: "feature"}))
+	assert.Equal(t, http.StatusGone, serve(router, "GET", "/feature", "").Code)
+}
+
+// Test flipping flags through the admin API
+func TestAdmin(t *testing.T) {
+	s := NewStore()
+	s.Define(Flag{Name: "feature", Percentage: Percent(0)})
+
+	router := gin.Default()
+	RegisterAdmin(router, s)
+
+	rw := serve(router, "GET", "/flags", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"percentage":0`)
+
+	l := []Flag{}
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &l))
+	assert.Equal(t, []Flag{{Name: "feature", Percentage: Percent(0)}}, l)
+
+	rw = serve(router, "PUT", "/flags/feature", `{"enabled": true, "percentage": 50}`)
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	f, _ := s.Get("feature")
+	assert.Equal(t, Flag{Name: "feature", Enabled: true, Percentage: Percent(50)}, f)
+
+	// Subjects without a percentage only turn the flag on for them
+	assert.Equal(t, http.StatusOK, serve(router, "PUT", "/flags/feature", `{"enabled": true, "subjects": ["beta"]}`).Code)
+	assert.True(t, s.Enabled(WithSubject(context.Background(), "beta"), "feature"))
+	assert.False(t, s.Enabled(WithSubject(context.Background(), "other"), "feature"))
+
+	// Enabling without a percentage turns the flag on for everyone
+	rw = serve(router, "PUT", "/flags/feature", `{"enabled": true}`)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"percentage":null`)
+	assert.True(t, s.Enabled(context.Background(), "feature"))
+
+	assert.Equal(t, http.StatusOK, serve(router, "GET", "/flags/feature", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, "GET", "/flags/unknown", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(router, "PUT", "/flags/feature", `{"percentage": 200}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(router, "PUT", "/flags/feature", `nope`).Code)
+}
@@ -0,0 +1,199 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Algorithms of the only envelope accepted, RSA-OAEP-256 wrapping an
+// A256GCM content key
+const (
+	KeyAlgorithm      = "RSA-OAEP-256"
+	ContentEncryption = "A256GCM"
+)
+
+// ErrInvalidToken is returned for any token that could not be decrypted, so
+// callers cannot tell which step failed
+var ErrInvalidToken = errors.New("invalid JWE token")
+
+type header struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid"`
+}
+
+// KeySet decrypts JWE compact tokens with the private key named by their
+// kid. Keys are rotated by adding the new one and removing the old one once
+// clients stopped using it.
+type KeySet struct {
+	keys map[string]*rsa.PrivateKey
+}
+
+func NewKeySet(keys map[string]*rsa.PrivateKey) *KeySet {
+	return &KeySet{
+		keys: keys,
+	}
+}
+
+// LoadKeySet reads the PEM private keys of dir, each named after its kid as
+// <kid>.pem
+func LoadKeySet(dir string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no JWE keys found in %s", dir)
+	}
+
+	keys := map[string]*rsa.PrivateKey{}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("JWE key %s: %w", f, err)
+		}
+
+		keys[strings.TrimSuffix(filepath.Base(f), ".pem")] = key
+	}
+
+	return NewKeySet(keys), nil
+}
+
+func parseKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key is not an RSA key")
+	}
+
+	return rsaKey, nil
+}
+
+// Decrypt returns the plaintext sealed in a JWE compact token
+func (k *KeySet) Decrypt(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, ErrInvalidToken
+	}
+
+	raw := make([][]byte, len(parts))
+	for i, p := range parts {
+		b, err := base64.RawURLEncoding.DecodeString(p)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		raw[i] = b
+	}
+
+	h := header{}
+	if err := json.Unmarshal(raw[0], &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, ok := k.keys[h.Kid]
+	if !ok || h.Alg != KeyAlgorithm || h.Enc != ContentEncryption {
+		return nil, ErrInvalidToken
+	}
+
+	cek, err := rsa.DecryptOAEP(sha256.New(), nil, key, raw[1], nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil || len(raw[2]) != gcm.NonceSize() || len(raw[4]) != gcm.Overhead() {
+		return nil, ErrInvalidToken
+	}
+
+	// The protected header, as encoded, is the additional authenticated data
+	plaintext, err := gcm.Open(nil, raw[2], append(raw[3], raw[4]...), []byte(parts[0]))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return plaintext, nil
+}
+
+// Encrypt seals plaintext in a JWE compact token for the public key named kid
+func Encrypt(pub *rsa.PublicKey, kid string, plaintext []byte) (string, error) {
+	h, err := json.Marshal(header{Alg: KeyAlgorithm, Enc: ContentEncryption, Kid: kid})
+	if err != nil {
+		return "", err
+	}
+
+	cek := make([]byte, 32)
+	if _, err := rand.Read(cek); err != nil {
+		return "", err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, cek, nil)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	protected := base64.RawURLEncoding.EncodeToString(h)
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{
+		protected,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != 32 {
+		return nil, ErrInvalidToken
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,82 @@
+package jwe
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	return key
+}
+
+// Test decrypting tokens sealed for any key of the set
+func TestDecrypt(t *testing.T) {
+	current, previous := newKey(t), newKey(t)
+	keys := NewKeySet(map[string]*rsa.PrivateKey{"2021-10": current, "2021-09": previous})
+
+	for kid, key := range map[string]*rsa.PrivateKey{"2021-10": current, "2021-09": previous} {
+		token, err := Encrypt(&key.PublicKey, kid, []byte("secret seed"))
+		assert.NoError(t, err)
+		assert.NotContains(t, token, "secret")
+
+		plaintext, err := keys.Decrypt(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret seed", string(plaintext))
+	}
+}
+
+// Test that tampered, unknown and malformed tokens are rejected
+func TestDecryptInvalid(t *testing.T) {
+	key := newKey(t)
+	keys := NewKeySet(map[string]*rsa.PrivateKey{"current": key})
+
+	token, err := Encrypt(&key.PublicKey, "current", []byte("secret seed"))
+	assert.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	tampered := append([]string{}, parts...)
+	tampered[3] = strings.Repeat("A", len(parts[3]))
+
+	unknown, err := Encrypt(&newKey(t).PublicKey, "retired", []byte("secret seed"))
+	assert.NoError(t, err)
+
+	wrongKey, err := Encrypt(&newKey(t).PublicKey, "current", []byte("secret seed"))
+	assert.NoError(t, err)
+
+	for _, token := range []string{"", "a.b.c", strings.Join(tampered, "."), unknown, wrongKey} {
+		_, err := keys.Decrypt(token)
+		assert.ErrorIs(t, err, ErrInvalidToken, token)
+	}
+}
+
+// Test loading keys named after their kid
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	key := newKey(t)
+
+	b := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "current.pem"), b, 0600))
+
+	keys, err := LoadKeySet(dir)
+	assert.NoError(t, err)
+
+	token, err := Encrypt(&key.PublicKey, "current", []byte("seed"))
+	assert.NoError(t, err)
+
+	plaintext, err := keys.Decrypt(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "seed", string(plaintext))
+
+	_, err = LoadKeySet(t.TempDir())
+	assert.Error(t, err)
+}
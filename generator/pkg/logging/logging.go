@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Header carrying the ID correlating a request across services
+const RequestIDHeader = "X-Request-ID"
+
+// Longest request ID accepted from callers
+const maxRequestIDLength = 128
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	loggerKey
+)
+
+// RequestID reuses the request ID sent by the caller or assigns a new one,
+// returning it to the caller and storing it in the request context
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey, id))
+
+		c.Next()
+	}
+}
+
+// Middleware stores a logger tagged with the request ID, and the trace ID
+// when installed after tracing, in the request context and logs the outcome
+// of every request. Only the route pattern is logged, never the raw URL, as
+// query strings may carry seeds.
+func Middleware(log *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		l := log.With("request_id", RequestIDFromContext(c.Request.Context()))
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			l = l.With("trace_id", sc.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), loggerKey, l))
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"route", route,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "errors", c.Errors.String())
+		}
+
+		switch status := c.Writer.Status(); {
+		case status >= http.StatusInternalServerError:
+			l.Errorw("request", fields...)
+		case status >= http.StatusBadRequest:
+			l.Warnw("request", fields...)
+		default:
+			l.Infow("request", fields...)
+		}
+	}
+}
+
+// Recovery turns panics into internal server errors, logging them with the
+// request logger
+func Recovery(log *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				FromContext(c.Request.Context(), log).Errorw("panic recovered", "panic", r, "stack", string(debug.Stack()))
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the ID of the request being served, if any
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// FromContext returns the logger of the request being served, or fallback
+// outside of a request
+func FromContext(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if l, ok := ctx.Value(loggerKey).(*zap.SugaredLogger); ok {
+		return l
+	}
+	return fallback
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
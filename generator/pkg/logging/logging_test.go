@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newRouter() (*gin.Engine, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core).Sugar()
+
+	router := gin.New()
+	router.Use(RequestID(), Middleware(log), Recovery(log))
+
+	router.GET("/test/:id", func(c *gin.Context) {
+		FromContext(c.Request.Context(), nil).Info("handling")
+		c.String(http.StatusOK, "OK")
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	return router, logs
+}
+
+// Test that requests are logged with their request ID and route
+func TestMiddleware(t *testing.T) {
+	router, logs := newRouter()
+
+	req := httptest.NewRequest("GET", "/test/1?seed=secret", nil)
+	req.Header.Set(RequestIDHeader, "abc")
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, "abc", rw.Header().Get(RequestIDHeader))
+	assert.Equal(t, 2, logs.Len())
+
+	for _, e := range logs.All() {
+		assert.Equal(t, "abc", e.ContextMap()["request_id"])
+	}
+
+	e := logs.All()[1].ContextMap()
+	assert.Equal(t, "/test/:id", e["route"])
+	assert.Equal(t, int64(http.StatusOK), e["status"])
+
+	for _, e := range logs.All() {
+		for _, v := range e.ContextMap() {
+			s, ok := v.(string)
+			assert.False(t, ok && strings.Contains(s, "secret"))
+		}
+	}
+}
+
+// Test that request logs carry the ID of the trace they belong to
+func TestMiddlewareTraceID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), sc))
+	}, Middleware(zap.New(core).Sugar()))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logs.All()[0].ContextMap()["trace_id"])
+}
+
+// Test that invalid request IDs are replaced
+func TestRequestIDGenerated(t *testing.T) {
+	router, _ := newRouter()
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set(RequestIDHeader, "not valid")
+	rw := httptest.NewRecorder()
+
+	router.ServeHTTP(rw, req)
+
+	assert.Len(t, rw.Header().Get(RequestIDHeader), 32)
+}
+
+// Test that panics are logged and answered with an error
+func TestRecovery(t *testing.T) {
+	router, logs := newRouter()
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("GET", "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, 1, logs.FilterMessage("panic recovered").Len())
+	assert.Equal(t, zap.ErrorLevel, logs.FilterMessage("request").All()[0].Level)
+}
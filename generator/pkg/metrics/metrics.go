@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_request_duration_seconds",
+	Help:    "Duration of HTTP requests by method, route and status.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+// Middleware records the duration of every request, labelled by the route
+// pattern rather than the path to keep cardinality bounded
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		requestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Register serves the metrics of the default registry at /metrics
+func Register(r gin.IRouter) {
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that requests are recorded and exposed
+func TestMiddleware(t *testing.T) {
+	router := gin.Default()
+	router.Use(Middleware())
+
+	Register(router)
+	router.GET("/test/:id", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "OK")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `http_request_duration_seconds_count{method="GET",route="/test/:id",status="418"} 1`)
+	assert.Contains(t, rw.Body.String(), `http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
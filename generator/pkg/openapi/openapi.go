@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document is the subset of an OpenAPI 3 document used to describe our services
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps a lowercase HTTP method to its operation
+type PathItem map[string]Operation
+
+type Operation struct {
+	Summary     string              `json:"summary"`
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string            `json:"$ref,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Minimum     *int64            `json:"minimum,omitempty"`
+	Maximum     *int64            `json:"maximum,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Properties  map[string]Schema `json:"properties,omitempty"`
+	Required    []string          `json:"required,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]Schema `json:"schemas,omitempty"`
+}
+
+// Ref references a schema declared in the document components
+func Ref(name string) Schema {
+	return Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Int returns a pointer to n, for schema bounds
+func Int(n int64) *int64 {
+	return &n
+}
+
+// JSON describes a response with a JSON body
+func JSON(description string, s Schema) Response {
+	return Response{
+		Description: description,
+		Content: map[string]MediaType{
+			"application/json": {Schema: s},
+		},
+	}
+}
+
+//go:embed swagger.html
+var swaggerUI []byte
+
+// Swagger UI assets vendored with make swagger-ui, so the docs page does not
+// load scripts from a third party
+//
+//go:embed swagger-ui
+var swaggerAssets embed.FS
+
+// Assets loaded by swagger.html, the only files served from swaggerAssets
+var swaggerFiles = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// Register serves the document at /openapi.json and a Swagger UI at /docs
+func Register(r gin.IRouter, doc Document) {
+	assets, _ := fs.Sub(swaggerAssets, "swagger-ui")
+
+	r.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	})
+
+	r.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+	})
+
+	for _, name := range swaggerFiles {
+		name := name
+		r.GET("/docs/"+name, func(c *gin.Context) {
+			c.FileFromFS(name, http.FS(assets))
+		})
+	}
+}
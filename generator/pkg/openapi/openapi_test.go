@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test serving the document and the Swagger UI
+func TestRegister(t *testing.T) {
+	router := gin.Default()
+
+	Register(router, Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "test", Version: "1.0.0"},
+		Paths: map[string]PathItem{
+			"/test": {"get": {OperationID: "test", Responses: map[string]Response{
+				"200": JSON("OK", Ref("Test")),
+			}}},
+		},
+	})
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	doc := Document{}
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+	assert.Equal(t, "#/components/schemas/Test", doc.Paths["/test"]["get"].Responses["200"].Content["application/json"].Schema.Ref)
+
+	req = httptest.NewRequest("GET", "/docs", nil)
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), "swagger-ui")
+	assert.NotContains(t, rw.Body.String(), "https://")
+}
+
+// Test that the Swagger UI assets are served from the binary
+func TestRegisterAssets(t *testing.T) {
+	router := gin.Default()
+
+	Register(router, Document{})
+
+	for _, path := range []string{"/docs/swagger-ui.css", "/docs/swagger-ui-bundle.js"} {
+		req := httptest.NewRequest("GET", path, nil)
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusOK, rw.Code, path)
+		assert.NotEmpty(t, rw.Body.Bytes(), path)
+	}
+
+	// Nothing else in the asset directory is exposed
+	for _, path := range []string{"/docs/", "/docs/README", "/docs/missing.js"} {
+		req := httptest.NewRequest("GET", path, nil)
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, req)
+
+		assert.NotEqual(t, http.StatusOK, rw.Code, path)
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Admin returns the /admin route group, only reachable with the given
+// bearer token
+func (s *Service) Admin(token string) *gin.RouterGroup {
+	return s.Router.Group("/admin", bearerAuth(token))
+}
+
+func bearerAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := bearer(c.GetHeader("Authorization"))
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "unauthorized",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func bearer(header string) (string, bool) {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return header[len(prefix):], true
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that admin routes require the token
+func TestAdmin(t *testing.T) {
+	s, err := New("test", ":0")
+	assert.NoError(t, err)
+
+	s.Admin("secret").GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	for auth, status := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"secret":        http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		req := httptest.NewRequest("GET", "/admin/test", nil)
+		req.Header.Set("Authorization", auth)
+		rw := httptest.NewRecorder()
+
+		s.Router.ServeHTTP(rw, req)
+
+		assert.Equal(t, status, rw.Code, auth)
+	}
+}
+
+// Test that an empty token never grants access
+func TestAdminEmptyToken(t *testing.T) {
+	s, err := New("test", ":0")
+	assert.NoError(t, err)
+
+	s.Admin("").GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest("GET", "/admin/test", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rw := httptest.NewRecorder()
+
+	s.Router.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+}
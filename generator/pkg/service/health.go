@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Time each readiness check has to report before it is considered failed
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency of the service is usable
+type Check func(ctx context.Context) error
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// AddReadinessCheck registers a dependency that must be reachable for the
+// service to receive traffic
+func (s *Service) AddReadinessCheck(name string, check Check) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+
+	s.checks[name] = check
+}
+
+func (s *Service) registerHealth(r *gin.Engine) {
+	// Kept for probes configured before /healthz existed
+	r.GET("/health", liveness)
+	r.GET("/healthz", liveness)
+	r.GET("/readyz", s.readiness)
+}
+
+func liveness(c *gin.Context) {
+	c.String(http.StatusOK, "OK")
+}
+
+func (s *Service) readiness(c *gin.Context) {
+	if atomic.LoadInt32(&s.draining) == 1 {
+		c.JSON(http.StatusServiceUnavailable, readiness{Status: "draining"})
+		return
+	}
+
+	s.checksMu.RLock()
+	checks := make(map[string]Check, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.checksMu.RUnlock()
+
+	res := readiness{
+		Status: "ok",
+		Checks: make(map[string]checkResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+			defer cancel()
+
+			r := checkResult{Status: "ok"}
+			if err := runCheck(ctx, check); err != nil {
+				r = checkResult{Status: "failed", Error: err.Error()}
+			}
+
+			mu.Lock()
+			res.Checks[name] = r
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for _, r := range res.Checks {
+		if r.Status != "ok" {
+			res.Status = "failed"
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	c.JSON(status, res)
+}
+
+// runCheck runs check, giving up once ctx is done even if the check ignores it
+func runCheck(ctx context.Context, check Check) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check(ctx)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(s *Service, path string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	s.Router.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+	return rw
+}
+
+// Test the liveness endpoint
+func TestLiveness(t *testing.T) {
+	s, err := New("test", ":0")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, get(s, "/healthz").Code)
+}
+
+// Test readiness with healthy and failing dependencies
+func TestReadiness(t *testing.T) {
+	s, err := New("test", ":0")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, get(s, "/readyz").Code)
+
+	s.AddReadinessCheck("db", func(ctx context.Context) error {
+		return nil
+	})
+	s.AddReadinessCheck("broker", func(ctx context.Context) error {
+		return fmt.Errorf("connection refused")
+	})
+
+	rw := get(s, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	res := readiness{}
+	assert.NoError(t, json.Unmarshal(rw.Body.Bytes(), &res))
+	assert.Equal(t, "failed", res.Status)
+	assert.Equal(t, checkResult{Status: "ok"}, res.Checks["db"])
+	assert.Equal(t, checkResult{Status: "failed", Error: "connection refused"}, res.Checks["broker"])
+}
+
+// Test that checks ignoring their context still time out
+func TestRunCheckTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	err := runCheck(ctx, func(context.Context) error {
+		<-block
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ezegrosfeld/wallet/generator/pkg/logging"
+	"github.com/ezegrosfeld/wallet/generator/pkg/metrics"
+	"github.com/ezegrosfeld/wallet/generator/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Default time given to the service to drain once a shutdown is requested
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Default time requests keep being served with readiness failed, so
+// orchestrators stop routing traffic before the listener closes
+const DefaultDrainDelay = 5 * time.Second
+
+// Service holds the pieces shared by every service binary
+type Service struct {
+	Name   string
+	Log    *zap.SugaredLogger
+	Router *gin.Engine
+
+	// Deadline for draining in-flight requests and running shutdown hooks
+	ShutdownTimeout time.Duration
+	// Time spent serving with readiness failed before the listener closes,
+	// counted within ShutdownTimeout
+	DrainDelay time.Duration
+
+	server   *http.Server
+	shutdown []func(context.Context) error
+	draining int32
+
+	checksMu sync.RWMutex
+	checks   map[string]Check
+}
+
+// New creates a service listening on addr with its logger, router, request
+// logging, metrics, tracing and health checks already set up
+func New(name string, addr string) (*Service, error) {
+	// Create a new logger
+	l, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+
+	log := l.Sugar().Named(name)
+
+	flushTraces, err := tracing.Setup(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new gin router
+	router := gin.New()
+	// Tracing goes before logging so request logs carry the trace ID
+	router.Use(
+		logging.RequestID(),
+		tracing.Middleware(name),
+		logging.Middleware(log),
+		logging.Recovery(log),
+		metrics.Middleware(),
+	)
+
+	metrics.Register(router)
+
+	s := &Service{
+		Name:            name,
+		Log:             log,
+		Router:          router,
+		ShutdownTimeout: DefaultShutdownTimeout,
+		DrainDelay:      DefaultDrainDelay,
+		server: &http.Server{
+			Addr:    addr,
+			Handler: router,
+		},
+		checks: map[string]Check{},
+	}
+
+	s.OnShutdown(flushTraces)
+
+	s.registerHealth(router)
+
+	return s, nil
+}
+
+// OnShutdown registers a function closing a resource once in-flight requests
+// are drained, hooks run in reverse order of registration
+func (s *Service) OnShutdown(f func(ctx context.Context) error) {
+	s.shutdown = append(s.shutdown, f)
+}
+
+// Run serves requests until the process receives SIGINT or SIGTERM. A second
+// signal during shutdown exits right away.
+func (s *Service) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return s.serve(ctx, stop)
+}
+
+// serve listens on the service address and serves requests until ctx is
+// done, then calls stop and shuts the server down
+func (s *Service) serve(ctx context.Context, stop func()) error {
+	defer s.Log.Sync()
+
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.serveListener(ctx, stop, ln)
+}
+
+func (s *Service) serveListener(ctx context.Context, stop func(), ln net.Listener) error {
+	errs := make(chan error, 1)
+	go func() {
+		s.Log.Infow("starting server", "addr", ln.Addr().String())
+		errs <- s.server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Restore the default signal handling so operators can force an exit
+	stop()
+
+	s.Log.Infow("shutting down server", "timeout", s.ShutdownTimeout, "drain_delay", s.DrainDelay)
+
+	sctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	// Fail readiness and keep serving so probes see it and traffic moves
+	// away before the listener closes
+	atomic.StoreInt32(&s.draining, 1)
+
+	select {
+	case <-time.After(s.DrainDelay):
+	case <-sctx.Done():
+	}
+
+	// Stops accepting connections and waits for in-flight requests
+	err := s.server.Shutdown(sctx)
+	if err != nil {
+		s.Log.Errorw("could not drain requests", "error", err)
+	}
+
+	for i := len(s.shutdown) - 1; i >= 0; i-- {
+		if ferr := s.shutdown[i](sctx); ferr != nil {
+			s.Log.Errorw("shutdown hook failed", "error", ferr)
+		}
+	}
+
+	return err
+}
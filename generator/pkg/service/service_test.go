@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the health check registered on every service
+func TestHealthCheck(t *testing.T) {
+	s, err := New("test", ":0")
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/health", nil)
+
+	rw := httptest.NewRecorder()
+
+	s.Router.ServeHTTP(rw, r)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+// Test that on shutdown the server keeps serving with readiness failed for
+// the drain delay, then stops and runs its hooks in reverse order
+func TestServeShutdown(t *testing.T) {
+	s, err := New("test", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	s.DrainDelay = 500 * time.Millisecond
+	s.ShutdownTimeout = 5 * time.Second
+
+	var closed []string
+	s.OnShutdown(func(ctx context.Context) error {
+		closed = append(closed, "db")
+		return nil
+	})
+	s.OnShutdown(func(ctx context.Context) error {
+		closed = append(closed, "broker")
+		return fmt.Errorf("already closed")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- s.serveListener(ctx, func() { close(stopped) }, ln)
+	}()
+
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{DisableKeepAlives: true},
+	}
+	readyz := func() (int, error) {
+		res, err := client.Get("http://" + ln.Addr().String() + "/readyz")
+		if err != nil {
+			return 0, err
+		}
+		res.Body.Close()
+		return res.StatusCode, nil
+	}
+
+	code, err := readyz()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	cancel()
+
+	// Signals are released as soon as shutdown starts, not once it is over
+	select {
+	case <-stopped:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("signals were not released on shutdown")
+	}
+
+	// Readiness fails over the network while the listener is still open
+	assert.Eventually(t, func() bool {
+		code, err := readyz()
+		return err == nil && code == http.StatusServiceUnavailable
+	}, 400*time.Millisecond, 10*time.Millisecond)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+
+	_, err = readyz()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"broker", "db"}, closed)
+}
+
+// Test that listen errors are returned
+func TestServeListenError(t *testing.T) {
+	s, err := New("test", "invalid-address")
+	assert.NoError(t, err)
+
+	assert.Error(t, s.serve(context.Background(), func() {}))
+}
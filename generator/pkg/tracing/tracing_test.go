@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Test that the trace of the caller is continued by the middleware
+func TestMiddlewarePropagation(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "test")
+	assert.NoError(t, err)
+	defer shutdown(context.Background())
+
+	var traceID string
+
+	router := gin.Default()
+	router.Use(Middleware("test"))
+	router.GET("/test", func(c *gin.Context) {
+		traceID = trace.SpanContextFromContext(c.Request.Context()).TraceID().String()
+		c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+}
+
+// Test that query strings, which may carry seeds, never reach spans
+func TestMiddlewareQueryRedacted(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	var query, uri string
+
+	router := gin.Default()
+	router.Use(middleware("test", tp))
+	router.GET("/wallet/", func(c *gin.Context) {
+		query, uri = c.Query("seed"), c.Request.RequestURI
+		c.String(http.StatusOK, "OK")
+	})
+
+	for _, url := range []string{"/wallet/?seed=abandon%20secret&index=0", "/missing?seed=abandon%20secret"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+
+	// Handlers still see the request as sent
+	assert.Equal(t, "abandon secret", query)
+	assert.Equal(t, "/wallet/?seed=abandon%20secret&index=0", uri)
+
+	spans := sr.Ended()
+	assert.Len(t, spans, 2)
+
+	for _, s := range spans {
+		for _, a := range s.Attributes() {
+			v := a.Value.Emit()
+			assert.False(t, strings.Contains(v, "secret") || strings.Contains(v, "seed"), "%s=%s", a.Key, v)
+		}
+	}
+
+	for _, a := range spans[0].Attributes() {
+		if a.Key == semconv.HTTPTargetKey {
+			assert.Equal(t, "/wallet/", a.Value.AsString())
+		}
+	}
+}